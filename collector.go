@@ -0,0 +1,157 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// packageCollector implements prometheus.Collector for a single detected
+// PackageManager, exposing normalized metric names shared across backends.
+type packageCollector struct {
+	manager PackageManager
+	logger  *slog.Logger
+	errors  *prometheus.CounterVec
+
+	pending           *prometheus.Desc
+	obsolete          *prometheus.Desc
+	autoremove        *prometheus.Desc
+	rebootRequired    *prometheus.Desc
+	securityPending   *prometheus.Desc
+	packageInfo       *prometheus.Desc
+	lastScrapeSuccess *prometheus.Desc
+	scrapeDuration    *prometheus.Desc
+}
+
+// scrapeStatter is implemented by package managers (namely cachedManager)
+// that can report when they last refreshed successfully and how long it took.
+type scrapeStatter interface {
+	ScrapeStats() (time.Time, time.Duration)
+}
+
+func newPackageCollector(manager PackageManager, logger *slog.Logger, errors *prometheus.CounterVec) *packageCollector {
+	return &packageCollector{
+		manager: manager,
+		logger:  logger,
+		errors:  errors,
+		pending: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "pending"),
+			"Number of packages pending an update",
+			[]string{"manager", "origin"}, nil,
+		),
+		obsolete: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "obsolete"),
+			"Number of obsolete packages",
+			[]string{"manager", "origin"}, nil,
+		),
+		autoremove: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "autoremove_pending"),
+			"Number of packages that are candidates for autoremove",
+			[]string{"manager"}, nil,
+		),
+		rebootRequired: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "reboot_required"),
+			"Whether a reboot is required to apply updates",
+			[]string{"manager"}, nil,
+		),
+		securityPending: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "security_updates_pending"),
+			"Number of pending security updates",
+			[]string{"origin", "severity"}, nil,
+		),
+		packageInfo: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "package_info"),
+			"Info metric with value 1 for each package with a pending update",
+			[]string{"name", "current_version", "available_version", "origin"}, nil,
+		),
+		lastScrapeSuccess: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "last_scrape_success_timestamp_seconds"),
+			"Unix timestamp of the last successful cache refresh for this backend",
+			[]string{"manager"}, nil,
+		),
+		scrapeDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "scrape_duration_seconds"),
+			"How long the last cache refresh for this backend took",
+			[]string{"manager"}, nil,
+		),
+	}
+}
+
+func (collector *packageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.pending
+	ch <- collector.obsolete
+	ch <- collector.autoremove
+	ch <- collector.rebootRequired
+	ch <- collector.securityPending
+	ch <- collector.packageInfo
+	ch <- collector.lastScrapeSuccess
+	ch <- collector.scrapeDuration
+}
+
+// logError logs err with the failing backend and stage, and increments
+// package_exporter_scrape_errors_total for it instead of silently dropping
+// the rest of the scrape.
+func (collector *packageCollector) logError(backend, stage string, err error) {
+	collector.logger.Error("error collecting package metrics", "backend", backend, "stage", stage, "err", err)
+	collector.errors.WithLabelValues(backend, stage).Inc()
+}
+
+func (collector *packageCollector) Collect(ch chan<- prometheus.Metric) {
+	name := collector.manager.Name()
+
+	pending, err := collector.manager.PendingUpdates()
+	if err != nil {
+		collector.logError(name, "pending", err)
+	}
+	for origin, packages := range pending {
+		ch <- prometheus.MustNewConstMetric(collector.pending, prometheus.GaugeValue, float64(len(packages)), name, origin)
+	}
+
+	obsolete, err := collector.manager.Obsoletes()
+	if err != nil {
+		collector.logError(name, "obsolete", err)
+	}
+	for origin, packages := range obsolete {
+		ch <- prometheus.MustNewConstMetric(collector.obsolete, prometheus.GaugeValue, float64(len(packages)), name, origin)
+	}
+
+	autoremove, err := collector.manager.AutoremoveCandidates()
+	if err != nil {
+		collector.logError(name, "autoremove", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(collector.autoremove, prometheus.GaugeValue, float64(autoremove), name)
+	}
+
+	rebootRequired, err := collector.manager.RebootRequired()
+	if err != nil {
+		collector.logError(name, "reboot_required", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(collector.rebootRequired, prometheus.GaugeValue, boolToFloat(rebootRequired), name)
+	}
+
+	if securityAware, ok := collector.manager.(SecurityAware); ok {
+		securityUpdates, err := securityAware.SecurityUpdates()
+		if err != nil {
+			collector.logError(name, "security_updates", err)
+		}
+		for _, update := range securityUpdates {
+			ch <- prometheus.MustNewConstMetric(collector.securityPending, prometheus.GaugeValue, float64(update.Count), update.Origin, update.Severity)
+		}
+
+		packages, err := securityAware.PackageDetails()
+		if err != nil {
+			collector.logError(name, "package_info", err)
+		}
+		for _, pkg := range packages {
+			ch <- prometheus.MustNewConstMetric(collector.packageInfo, prometheus.GaugeValue, 1, pkg.Name, pkg.CurrentVersion, pkg.AvailableVersion, pkg.Origin)
+		}
+	}
+
+	if statter, ok := collector.manager.(scrapeStatter); ok {
+		lastSuccess, duration := statter.ScrapeStats()
+		if !lastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(collector.lastScrapeSuccess, prometheus.GaugeValue, float64(lastSuccess.Unix()), name)
+		}
+		ch <- prometheus.MustNewConstMetric(collector.scrapeDuration, prometheus.GaugeValue, duration.Seconds(), name)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
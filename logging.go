@@ -0,0 +1,13 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// newLogger returns the exporter's structured logger: key=value lines with a
+// timestamp, e.g. "time=... level=ERROR msg=... backend=apt stage=pending
+// err=...".
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, nil))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/PovilasV1/package_exporter/pkg/backend"
+)
+
+// PackageManager abstracts over the distro-specific tooling used to discover
+// pending updates, obsolete packages, autoremove candidates and whether a
+// reboot is required. Each supported package manager (yum, apt, dnf, pacman,
+// apk, zypper) provides its own implementation.
+type PackageManager interface {
+	// Name identifies the backend for metric labelling, e.g. "apt" or "apk".
+	Name() string
+	// Detect reports whether this package manager is available on the host.
+	Detect() bool
+	// PendingUpdates returns the packages pending an update, keyed by origin.
+	PendingUpdates() (map[string][]string, error)
+	// Obsoletes returns obsolete/orphaned packages, keyed by origin.
+	Obsoletes() (map[string][]string, error)
+	// AutoremoveCandidates returns the number of packages that could be removed.
+	AutoremoveCandidates() (int, error)
+	// RebootRequired reports whether the host needs a reboot to apply updates.
+	RebootRequired() (bool, error)
+}
+
+// detectPackageManager tries each known backend's "--version" command and
+// returns the first one found on the host. rpmTTL controls how often the
+// yum backend re-reads the local RPM database instead of spawning a fresh
+// rpm process per package lookup.
+func detectPackageManager(rpmTTL time.Duration) (PackageManager, error) {
+	rpmDB := backend.NewRPMDatabase(rpmTTL)
+	candidates := []PackageManager{
+		&aptManager{},
+		&yumManager{rpmDB: rpmDB},
+		&dnfManager{},
+		&zypperManager{},
+		&pacmanManager{},
+		&apkManager{},
+	}
+	for _, m := range candidates {
+		if m.Detect() {
+			return m, nil
+		}
+	}
+	return nil, errNoPackageManager
+}
+
+// commandExists reports whether name's "--version" invocation succeeds,
+// which is the cheapest portable way to check a package manager is installed.
+func commandExists(name string) bool {
+	cmd := exec.Command(name, "--version")
+	return cmd.Run() == nil
+}
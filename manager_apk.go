@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// apkManager collects metrics on Alpine-based systems using apk.
+type apkManager struct{}
+
+func (m *apkManager) Name() string { return "apk" }
+
+func (m *apkManager) Detect() bool { return commandExists("apk") }
+
+func (m *apkManager) PendingUpdates() (map[string][]string, error) {
+	// apk version -l '<' lists installed packages that are behind the
+	// configured repositories, one "pkg-version" per line.
+	cmd := exec.Command("/sbin/apk", "version", "-l", "<")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Installed") {
+			continue
+		}
+		packagesPerOrigin["main"] = append(packagesPerOrigin["main"], line)
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *apkManager) Obsoletes() (map[string][]string, error) {
+	// apk has no notion of obsolete packages distinct from pending updates.
+	return nil, nil
+}
+
+func (m *apkManager) AutoremoveCandidates() (int, error) {
+	// "apk fix --simulate" reports packages it would remove as orphaned deps.
+	cmd := exec.Command("/sbin/apk", "fix", "--simulate")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.Contains(line, "Purging") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *apkManager) RebootRequired() (bool, error) {
+	// Alpine has no standard reboot-required marker.
+	return false, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// aptManager collects metrics on Debian/Ubuntu systems using apt.
+type aptManager struct{}
+
+func (m *aptManager) Name() string { return "apt" }
+
+func (m *aptManager) Detect() bool { return commandExists("apt-get") }
+
+// aptInst holds one parsed "Inst" line from apt-get's --just-print output.
+type aptInst struct {
+	name             string
+	currentVersion   string
+	availableVersion string
+	origin           string
+	arch             string
+}
+
+var (
+	aptInstRegex       = regexp.MustCompile(`^Inst`)
+	aptOriginRegex     = regexp.MustCompile(`\(([^)]+)\)`)
+	aptPackageRegex    = regexp.MustCompile(`\s([\S\s]+?)\s`)
+	aptCurrentVerRegex = regexp.MustCompile(`\[([^\]]+)\]`)
+	aptAvailVerRegex   = regexp.MustCompile(`\(([^ ]+)`)
+)
+
+// parseAptInstOutput parses the "Inst" lines printed by
+// "apt-get --just-print dist-upgrade" or "upgrade".
+func parseAptInstOutput(output []byte) []aptInst {
+	var insts []aptInst
+	for _, line := range strings.Split(string(output), "\n") {
+		if !aptInstRegex.MatchString(line) {
+			continue
+		}
+		nameMatch := aptPackageRegex.FindStringSubmatch(line)
+		match := aptOriginRegex.FindStringSubmatch(line)
+		if nameMatch == nil || match == nil {
+			continue
+		}
+		origins := strings.Split(match[1], " ")[1:]
+		if len(origins) == 0 {
+			continue
+		}
+		packageName := nameMatch[1]
+		originString := strings.Join(origins[:len(origins)-1], " ")
+		origin := strings.ReplaceAll(originString, ", ", ",")
+		arch := strings.Trim(origins[len(origins)-1], "[]")
+
+		inst := aptInst{name: packageName, origin: origin, arch: arch}
+		if m := aptCurrentVerRegex.FindStringSubmatch(line); m != nil {
+			inst.currentVersion = m[1]
+		}
+		if m := aptAvailVerRegex.FindStringSubmatch(line); m != nil {
+			inst.availableVersion = m[1]
+		}
+		insts = append(insts, inst)
+	}
+	return insts
+}
+
+func (m *aptManager) PendingUpdates() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/apt-get", "--just-print", "dist-upgrade")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, inst := range parseAptInstOutput(output) {
+		key := inst.origin + "/" + inst.arch
+		packagesPerOrigin[key] = append(packagesPerOrigin[key], inst.name)
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *aptManager) Obsoletes() (map[string][]string, error) {
+	// apt has no notion of "obsolete" packages distinct from autoremove
+	// candidates, so there is nothing to report here.
+	return nil, nil
+}
+
+func (m *aptManager) AutoremoveCandidates() (int, error) {
+	cmd := exec.Command("/usr/bin/apt-get", "--just-print", "autoremove")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	if len(output) == 0 {
+		return 0, nil
+	}
+
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Remv") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *aptManager) RebootRequired() (bool, error) {
+	if _, err := os.Stat("/run/reboot-required"); err == nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+// SecurityUpdates counts pending updates whose origin is a "-security" suite,
+// e.g. "Ubuntu:20.04/focal-security". apt has no severity classification of
+// its own, so every security update is reported as severity "unknown".
+func (m *aptManager) SecurityUpdates() ([]SecurityUpdate, error) {
+	cmd := exec.Command("/usr/bin/apt-get", "--just-print", "upgrade")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	countPerOrigin := make(map[string]int)
+	for _, inst := range parseAptInstOutput(output) {
+		if !strings.Contains(inst.origin, "security") {
+			continue
+		}
+		countPerOrigin[inst.origin]++
+	}
+
+	var updates []SecurityUpdate
+	for origin, count := range countPerOrigin {
+		updates = append(updates, SecurityUpdate{Origin: origin, Severity: "unknown", Count: count})
+	}
+	return updates, nil
+}
+
+// PackageDetails reuses the same "Inst" parsing as PendingUpdates but keeps
+// the current and available versions instead of collapsing to a count.
+func (m *aptManager) PackageDetails() ([]PackageInfo, error) {
+	cmd := exec.Command("/usr/bin/apt-get", "--just-print", "dist-upgrade")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var packages []PackageInfo
+	for _, inst := range parseAptInstOutput(output) {
+		packages = append(packages, PackageInfo{
+			Name:             inst.name,
+			CurrentVersion:   inst.currentVersion,
+			AvailableVersion: inst.availableVersion,
+			Origin:           inst.origin,
+		})
+	}
+	return packages, nil
+}
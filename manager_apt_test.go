@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAptInstOutput(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   []aptInst
+	}{
+		{
+			name:   "normal Inst line",
+			output: "Inst bash [4.4.20-3] (4.4.20-4 Ubuntu:20.04/focal-security [amd64])\n",
+			want: []aptInst{
+				{name: "bash", currentVersion: "4.4.20-3", availableVersion: "4.4.20-4", origin: "Ubuntu:20.04/focal-security", arch: "amd64"},
+			},
+		},
+		{
+			name:   "non-Inst lines are ignored",
+			output: "Reading package lists...\nBuilding dependency tree...\n",
+			want:   nil,
+		},
+		{
+			name:   "Inst line missing parens doesn't panic and is skipped",
+			output: "Inst bash\n",
+			want:   nil,
+		},
+		{
+			name:   "Inst line with empty parens doesn't panic and is skipped",
+			output: "Inst bash ()\n",
+			want:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseAptInstOutput([]byte(tc.output))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseAptInstOutput(%q) = %+v, want %+v", tc.output, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"time"
+
+	"github.com/PovilasV1/package_exporter/pkg/backend"
+)
+
+// cachedManager wraps a PackageManager so repeated Prometheus scrapes within
+// cacheTTL reuse the last result instead of re-running potentially expensive
+// or lock-holding commands (apt-get, yum, ...) on every scrape. Each method
+// gets its own backend.Cache so a slow Obsoletes() call can't stall Pending.
+type cachedManager struct {
+	inner PackageManager
+
+	pending        *backend.Cache
+	obsolete       *backend.Cache
+	autoremove     *backend.Cache
+	rebootRequired *backend.Cache
+}
+
+// securityAwareCachedManager adds cached SecurityUpdates/PackageDetails on
+// top of cachedManager. It's a distinct type, rather than cachedManager
+// always implementing both methods, so collector.go's
+// `manager.(SecurityAware)` type assertion stays false for backends that
+// don't actually support it, per SecurityAware's doc comment.
+type securityAwareCachedManager struct {
+	*cachedManager
+
+	security    *backend.Cache
+	packageInfo *backend.Cache
+}
+
+// newCachedManager wraps manager so its results are cached for ttl.
+func newCachedManager(manager PackageManager, ttl time.Duration) PackageManager {
+	c := &cachedManager{inner: manager}
+	c.pending = backend.NewCache(ttl, func() (interface{}, error) { return manager.PendingUpdates() })
+	c.obsolete = backend.NewCache(ttl, func() (interface{}, error) { return manager.Obsoletes() })
+	c.autoremove = backend.NewCache(ttl, func() (interface{}, error) { return manager.AutoremoveCandidates() })
+	c.rebootRequired = backend.NewCache(ttl, func() (interface{}, error) { return manager.RebootRequired() })
+
+	security, ok := manager.(SecurityAware)
+	if !ok {
+		return c
+	}
+	return &securityAwareCachedManager{
+		cachedManager: c,
+		security:      backend.NewCache(ttl, func() (interface{}, error) { return security.SecurityUpdates() }),
+		packageInfo:   backend.NewCache(ttl, func() (interface{}, error) { return security.PackageDetails() }),
+	}
+}
+
+func (c *cachedManager) Name() string { return c.inner.Name() }
+
+func (c *cachedManager) Detect() bool { return c.inner.Detect() }
+
+func (c *cachedManager) PendingUpdates() (map[string][]string, error) {
+	value, err := c.pending.Snapshot()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return value.(map[string][]string), nil
+}
+
+func (c *cachedManager) Obsoletes() (map[string][]string, error) {
+	value, err := c.obsolete.Snapshot()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return value.(map[string][]string), nil
+}
+
+func (c *cachedManager) AutoremoveCandidates() (int, error) {
+	value, err := c.autoremove.Snapshot()
+	if err != nil || value == nil {
+		return 0, err
+	}
+	return value.(int), nil
+}
+
+func (c *cachedManager) RebootRequired() (bool, error) {
+	value, err := c.rebootRequired.Snapshot()
+	if err != nil || value == nil {
+		return false, err
+	}
+	return value.(bool), nil
+}
+
+// ScrapeStats reports when PendingUpdates last refreshed successfully and
+// how long that refresh took, for the last_scrape_success_timestamp_seconds
+// and scrape_duration_seconds metrics.
+func (c *cachedManager) ScrapeStats() (time.Time, time.Duration) {
+	return c.pending.LastSuccess(), c.pending.LastDuration()
+}
+
+// SecurityUpdates implements SecurityAware.
+func (c *securityAwareCachedManager) SecurityUpdates() ([]SecurityUpdate, error) {
+	value, err := c.security.Snapshot()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return value.([]SecurityUpdate), nil
+}
+
+// PackageDetails implements SecurityAware.
+func (c *securityAwareCachedManager) PackageDetails() ([]PackageInfo, error) {
+	value, err := c.packageInfo.Snapshot()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return value.([]PackageInfo), nil
+}
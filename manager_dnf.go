@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// dnfManager collects metrics on RPM-based systems using dnf (Fedora/Rocky).
+type dnfManager struct{}
+
+func (m *dnfManager) Name() string { return "dnf" }
+
+func (m *dnfManager) Detect() bool { return commandExists("dnf") }
+
+func (m *dnfManager) PendingUpdates() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/dnf", "check-update", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		if err.Error() != "exit status 100" {
+			return nil, err
+		}
+	}
+
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(output), "\n")
+	packagesPerOrigin := make(map[string][]string)
+
+	for _, line := range lines {
+		if strings.Contains(line, ".") {
+			parts := strings.Fields(line)
+			if len(parts) < 3 {
+				continue
+			}
+			packageName := parts[0]
+			origin := parts[2]
+			packagesPerOrigin[origin] = append(packagesPerOrigin[origin], packageName)
+		}
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *dnfManager) Obsoletes() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/dnf", "list", "obsoletes", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(output), "\n")
+	packagesPerOrigin := make(map[string][]string)
+
+	for _, line := range lines {
+		if strings.Contains(line, ".") {
+			if !strings.HasPrefix(line, "    ") {
+				parts := strings.Fields(line)
+				if len(parts) < 3 {
+					continue
+				}
+				packageName := parts[0]
+				origin := parts[2]
+				packagesPerOrigin[origin] = append(packagesPerOrigin[origin], packageName)
+			}
+		}
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *dnfManager) AutoremoveCandidates() (int, error) {
+	// dnf's autoremove is a dry-run-only subcommand when combined with
+	// --assumeno, reporting removals on stderr-free stdout lines.
+	cmd := exec.Command("/usr/bin/dnf", "autoremove", "--assumeno", "--quiet")
+	output, _ := cmd.CombinedOutput()
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, " ") && strings.Contains(line, ".") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *dnfManager) RebootRequired() (bool, error) {
+	cmd := exec.Command("/usr/bin/needs-restarting", "-r")
+	cmd.Run()
+	return cmd.ProcessState.ExitCode() != 0, nil
+}
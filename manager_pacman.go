@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// pacmanManager collects metrics on Arch-based systems using pacman.
+// PendingUpdates is implemented separately per build tag: see
+// manager_pacman_alpm.go (-tags pacman, native libalpm reads) and
+// manager_pacman_shell.go (default, shells out to "pacman -Qu").
+type pacmanManager struct{}
+
+func (m *pacmanManager) Name() string { return "pacman" }
+
+func (m *pacmanManager) Detect() bool { return commandExists("pacman") }
+
+func (m *pacmanManager) Obsoletes() (map[string][]string, error) {
+	// pacman -Qdt lists orphaned dependencies, the closest analogue to obsoletes.
+	cmd := exec.Command("/usr/bin/pacman", "-Qdt")
+	output, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, err
+		}
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		packagesPerOrigin["local"] = append(packagesPerOrigin["local"], parts[0])
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *pacmanManager) AutoremoveCandidates() (int, error) {
+	packages, err := m.Obsoletes()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, pkgs := range packages {
+		count += len(pkgs)
+	}
+	return count, nil
+}
+
+func (m *pacmanManager) RebootRequired() (bool, error) {
+	// Arch has no standard reboot-required marker analogous to RHEL/Debian.
+	return false, nil
+}
@@ -0,0 +1,12 @@
+//go:build pacman
+
+package main
+
+import "github.com/PovilasV1/package_exporter/pkg/backend"
+
+// PendingUpdates reads the local and sync ALPM databases directly via
+// libalpm bindings. Requires building with -tags pacman (and libalpm's
+// headers installed); see manager_pacman_shell.go for the default build.
+func (m *pacmanManager) PendingUpdates() (map[string][]string, error) {
+	return backend.AlpmPendingUpdates()
+}
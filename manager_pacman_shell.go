@@ -0,0 +1,39 @@
+//go:build !pacman
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// PendingUpdates shells out to pacman, since the libalpm-backed
+// implementation in manager_pacman_alpm.go is only built with -tags pacman.
+// "pacman -Qu" lists "pkg oldver -> newver [repo]" once a sync DB refresh
+// has happened; it exits 1 when nothing is pending, which is not an error.
+func (m *pacmanManager) PendingUpdates() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/pacman", "-Qu")
+	output, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, err
+		}
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		origin := "core"
+		if len(parts) >= 5 && strings.HasPrefix(parts[len(parts)-1], "[") {
+			origin = strings.Trim(parts[len(parts)-1], "[]")
+		}
+		packagesPerOrigin[origin] = append(packagesPerOrigin[origin], parts[0])
+	}
+	return packagesPerOrigin, nil
+}
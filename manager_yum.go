@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/PovilasV1/package_exporter/pkg/backend"
+)
+
+// yumManager collects metrics on RPM-based systems using yum (RHEL/CentOS).
+type yumManager struct {
+	rpmDB *backend.RPMDatabase
+}
+
+func (m *yumManager) Name() string { return "yum" }
+
+func (m *yumManager) Detect() bool { return commandExists("yum") }
+
+func (m *yumManager) PendingUpdates() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/yum", "check-update", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		if err.Error() != "exit status 100" {
+			return nil, err
+		}
+	}
+	return parseYumCheckUpdate(output), nil
+}
+
+// parseYumCheckUpdate parses the package/origin lines printed by
+// "yum check-update", e.g. "bash.x86_64  4.4.20-3.el8  BaseOS".
+func parseYumCheckUpdate(output []byte) map[string][]string {
+	if len(output) == 0 {
+		return nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.Contains(line, ".") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		packagesPerOrigin[parts[2]] = append(packagesPerOrigin[parts[2]], parts[0])
+	}
+	return packagesPerOrigin
+}
+
+func (m *yumManager) Obsoletes() (map[string][]string, error) {
+	cmd := exec.Command("/usr/bin/yum", "list", "obsoletes", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseYumObsoletes(output), nil
+}
+
+// parseYumObsoletes parses the package/origin lines printed by
+// "yum list obsoletes", skipping the indented "replaced by" lines that
+// follow each obsoleted package.
+func parseYumObsoletes(output []byte) map[string][]string {
+	if len(output) == 0 {
+		return nil
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.Contains(line, ".") || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		packagesPerOrigin[parts[2]] = append(packagesPerOrigin[parts[2]], parts[0])
+	}
+	return packagesPerOrigin
+}
+
+func (m *yumManager) AutoremoveCandidates() (int, error) {
+	// yum has no first-class autoremove equivalent; package-cleanup --leaves
+	// isn't installed by default, so we report none rather than shell out to
+	// a tool that may not exist.
+	return 0, nil
+}
+
+func (m *yumManager) RebootRequired() (bool, error) {
+	cmd := exec.Command("/bin/needs-restarting", "-r")
+	cmd.Run()
+	return cmd.ProcessState.ExitCode() != 0, nil
+}
+
+// SecurityUpdates parses "yum updateinfo list security --quiet", whose lines
+// look like "FEDORA-2020-abcd Important/Sec  bash-4.4.20-3.el8.x86_64".
+func (m *yumManager) SecurityUpdates() ([]SecurityUpdate, error) {
+	cmd := exec.Command("/usr/bin/yum", "updateinfo", "list", "security", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		if err.Error() != "exit status 100" {
+			return nil, err
+		}
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	countPerSeverity := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		severity := strings.Split(parts[1], "/")[0]
+		countPerSeverity[severity]++
+	}
+
+	var updates []SecurityUpdate
+	for severity, count := range countPerSeverity {
+		updates = append(updates, SecurityUpdate{Origin: "yum-security", Severity: severity, Count: count})
+	}
+	return updates, nil
+}
+
+// PackageDetails parses "yum check-update" the same way PendingUpdates does,
+// but also keeps the available version and looks up the installed version
+// via rpm so dashboards can show the upgrade path for a package.
+func (m *yumManager) PackageDetails() ([]PackageInfo, error) {
+	cmd := exec.Command("/usr/bin/yum", "check-update", "--quiet")
+	output, err := cmd.Output()
+	if err != nil {
+		if err.Error() != "exit status 100" {
+			return nil, err
+		}
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var packages []PackageInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.Contains(line, ".") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
+		}
+		name := parts[0]
+		packages = append(packages, PackageInfo{
+			Name:             name,
+			CurrentVersion:   m.rpmDB.Version(strings.SplitN(name, ".", 2)[0]),
+			AvailableVersion: parts[1],
+			Origin:           parts[2],
+		})
+	}
+	return packages, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYumCheckUpdate(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   map[string][]string
+	}{
+		{
+			name: "normal lines",
+			output: "bash.x86_64        4.4.20-3.el8        BaseOS\n" +
+				"curl.x86_64        7.61.1-22.el8       BaseOS\n" +
+				"\n" +
+				"Obsoleting Packages\n",
+			want: map[string][]string{
+				"BaseOS": {"bash.x86_64", "curl.x86_64"},
+			},
+		},
+		{
+			name:   "empty output",
+			output: "",
+			want:   nil,
+		},
+		{
+			name:   "short line doesn't panic and is skipped",
+			output: "bash.x86_64\n",
+			want:   map[string][]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseYumCheckUpdate([]byte(tc.output))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseYumCheckUpdate(%q) = %v, want %v", tc.output, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseYumObsoletes(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		want   map[string][]string
+	}{
+		{
+			name: "obsolete with replacement line skipped",
+			output: "python2.x86_64    2.7.18-1.el8    @BaseOS\n" +
+				"    replaced by python3.x86_64 3.6.8-1.el8\n",
+			want: map[string][]string{
+				"@BaseOS": {"python2.x86_64"},
+			},
+		},
+		{
+			name:   "empty output",
+			output: "",
+			want:   nil,
+		},
+		{
+			name:   "short line doesn't panic and is skipped",
+			output: "python2.x86_64\n",
+			want:   map[string][]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseYumObsoletes([]byte(tc.output))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseYumObsoletes(%q) = %v, want %v", tc.output, got, tc.want)
+			}
+		})
+	}
+}
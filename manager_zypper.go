@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// zypperManager collects metrics on openSUSE/SLE systems using zypper.
+type zypperManager struct{}
+
+func (m *zypperManager) Name() string { return "zypper" }
+
+func (m *zypperManager) Detect() bool { return commandExists("zypper") }
+
+func (m *zypperManager) PendingUpdates() (map[string][]string, error) {
+	// zypper lu prints a pipe-delimited table whose update rows start "v |":
+	// S | Repository | Name | Current Version | Available Version | Arch
+	cmd := exec.Command("/usr/bin/zypper", "--quiet", "--non-interactive", "--no-refresh", "lu")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(output), "\n")
+	packagesPerOrigin := make(map[string][]string)
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "v |") {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) < 5 {
+			continue
+		}
+		origin := strings.TrimSpace(fields[1])
+		packageName := strings.TrimSpace(fields[2])
+		packagesPerOrigin[origin] = append(packagesPerOrigin[origin], packageName)
+	}
+	return packagesPerOrigin, nil
+}
+
+func (m *zypperManager) Obsoletes() (map[string][]string, error) {
+	// zypper has no distinct "obsoletes" listing outside of pending updates.
+	return nil, nil
+}
+
+func (m *zypperManager) AutoremoveCandidates() (int, error) {
+	cmd := exec.Command("/usr/bin/zypper", "--quiet", "--non-interactive", "packages", "--orphaned")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "i |") {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *zypperManager) RebootRequired() (bool, error) {
+	cmd := exec.Command("/usr/bin/needs-restarting", "-r")
+	cmd.Run()
+	return cmd.ProcessState.ExitCode() != 0, nil
+}
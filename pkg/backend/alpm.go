@@ -0,0 +1,51 @@
+//go:build pacman
+
+// This file cgo-links against libalpm, which isn't installed on non-Arch
+// hosts, so it's gated behind the "pacman" build tag. See
+// alpm_unavailable.go for the default build's error-returning stub; the
+// default shell-out to pacman lives in manager_pacman_shell.go.
+
+package backend
+
+import (
+	alpm "github.com/Jguer/go-alpm/v2"
+)
+
+// AlpmPendingUpdates walks the local and sync databases via libalpm bindings
+// instead of shelling out to "pacman -Qu", which requires the sync DBs to
+// have already been refreshed by the caller (e.g. via a timer running
+// "pacman -Sy" separately, same as the pacman -Qu approach it replaces).
+func AlpmPendingUpdates() (map[string][]string, error) {
+	h, err := alpm.Initialize("/", "/var/lib/pacman")
+	if err != nil {
+		return nil, err
+	}
+	defer h.Release()
+
+	localDB, err := h.LocalDB()
+	if err != nil {
+		return nil, err
+	}
+	syncDBs, err := h.SyncDBs()
+	if err != nil {
+		return nil, err
+	}
+
+	packagesPerOrigin := make(map[string][]string)
+	err = localDB.PkgCache().ForEach(func(local alpm.IPackage) error {
+		return syncDBs.ForEach(func(db alpm.IDB) error {
+			syncPkg := db.Pkg(local.Name())
+			if syncPkg == nil {
+				return nil
+			}
+			if alpm.VerCmp(local.Version(), syncPkg.Version()) < 0 {
+				packagesPerOrigin[db.Name()] = append(packagesPerOrigin[db.Name()], local.Name())
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packagesPerOrigin, nil
+}
@@ -0,0 +1,14 @@
+//go:build !pacman
+
+package backend
+
+import "errors"
+
+// AlpmPendingUpdates is a stand-in for the real libalpm-backed implementation
+// in alpm.go, built only when this binary is compiled with `-tags pacman`
+// (which requires libalpm's headers to be installed). Nothing in a default
+// build calls this: pacmanManager shells out to pacman instead, see
+// manager_pacman_shell.go.
+func AlpmPendingUpdates() (map[string][]string, error) {
+	return nil, errors.New("package_exporter was built without libalpm support (rebuild with -tags pacman)")
+}
@@ -0,0 +1,89 @@
+// Package backend holds the lower-level, per-distro data sources used by the
+// package manager collectors: persistent RPM/ALPM database reads and a
+// shared TTL cache so a slow refresh doesn't run once per Prometheus scrape.
+// It has no apt data source; the apt collector runs apt-get itself.
+package backend
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache memoizes the result of a slow Refresh function for TTL and refreshes
+// it asynchronously in the background so that, after the first call, callers
+// never block a scrape on it.
+// Snapshot, LastSuccess and LastDuration are safe for concurrent use.
+type Cache struct {
+	TTL     time.Duration
+	Refresh func() (interface{}, error)
+
+	initOnce sync.Once
+
+	mu             sync.Mutex
+	value          interface{}
+	err            error
+	lastRefresh    time.Time
+	lastSuccess    time.Time
+	lastDuration   time.Duration
+	refreshPending bool
+}
+
+// NewCache creates a Cache with the given TTL and refresh function. The
+// first Snapshot call always triggers a synchronous refresh so the exporter
+// has data to serve before any background refresh has run.
+func NewCache(ttl time.Duration, refresh func() (interface{}, error)) *Cache {
+	return &Cache{TTL: ttl, Refresh: refresh}
+}
+
+// Snapshot returns the most recently cached value, triggering a refresh if
+// the TTL has expired. The very first call blocks on a synchronous refresh
+// so callers never see an empty cache; every refresh after that runs in a
+// background goroutine so Snapshot never blocks a scrape on it.
+func (c *Cache) Snapshot() (interface{}, error) {
+	c.initOnce.Do(c.doRefresh)
+
+	c.mu.Lock()
+	stale := time.Since(c.lastRefresh) >= c.TTL
+	pending := c.refreshPending
+	value, err := c.value, c.err
+	if stale && !pending {
+		c.refreshPending = true
+		go c.doRefresh()
+	}
+	c.mu.Unlock()
+	return value, err
+}
+
+func (c *Cache) doRefresh() {
+	start := time.Now()
+	value, err := c.Refresh()
+	duration := time.Since(start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshPending = false
+	c.lastRefresh = time.Now()
+	c.lastDuration = duration
+	if err != nil {
+		c.err = err
+		return
+	}
+	c.value = value
+	c.err = nil
+	c.lastSuccess = time.Now()
+}
+
+// LastSuccess returns the time of the last successful refresh, the zero
+// time if none has succeeded yet.
+func (c *Cache) LastSuccess() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastSuccess
+}
+
+// LastDuration returns how long the most recent refresh attempt took.
+func (c *Cache) LastDuration() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastDuration
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheSnapshotFirstCallIsSynchronous(t *testing.T) {
+	c := NewCache(time.Hour, func() (interface{}, error) { return "value", nil })
+
+	value, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v, want nil", err)
+	}
+	if value != "value" {
+		t.Fatalf("Snapshot() = %v, want %q on the very first call", value, "value")
+	}
+}
+
+func TestCacheSnapshotReusesValueWithinTTL(t *testing.T) {
+	var calls int32
+	c := NewCache(time.Hour, func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return "value", nil
+	})
+
+	for i := 0; i < 5; i++ {
+		c.Snapshot()
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("Refresh called %d times within TTL, want 1", got)
+	}
+}
+
+func TestCacheSnapshotRefreshesAsyncAfterTTL(t *testing.T) {
+	var value atomic.Value
+	value.Store("first")
+	c := NewCache(10*time.Millisecond, func() (interface{}, error) {
+		return value.Load(), nil
+	})
+
+	if got, _ := c.Snapshot(); got != "first" {
+		t.Fatalf("Snapshot() = %v, want %q", got, "first")
+	}
+
+	value.Store("second")
+	time.Sleep(20 * time.Millisecond)
+	c.Snapshot() // triggers the async refresh
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := c.Snapshot(); got == "second" {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Snapshot() never converged to the refreshed value after the TTL expired")
+}
+
+func TestCacheLastSuccessAndDuration(t *testing.T) {
+	c := NewCache(time.Hour, func() (interface{}, error) { return "value", nil })
+
+	if !c.LastSuccess().IsZero() {
+		t.Fatalf("LastSuccess() = %v before the first Snapshot, want zero time", c.LastSuccess())
+	}
+
+	c.Snapshot()
+
+	if c.LastSuccess().IsZero() {
+		t.Fatal("LastSuccess() is still zero after a successful Snapshot")
+	}
+	if c.LastDuration() < 0 {
+		t.Fatalf("LastDuration() = %v, want >= 0", c.LastDuration())
+	}
+}
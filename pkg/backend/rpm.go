@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// RPMDatabase answers installed-version lookups from a single
+// "rpm -qa --queryformat" dump of the local RPM database, refreshed on a TTL,
+// instead of spawning a new rpm process per package per scrape.
+type RPMDatabase struct {
+	cache *Cache
+}
+
+// NewRPMDatabase creates an RPMDatabase that re-reads the local database at
+// most once per ttl.
+func NewRPMDatabase(ttl time.Duration) *RPMDatabase {
+	db := &RPMDatabase{}
+	db.cache = NewCache(ttl, db.query)
+	return db
+}
+
+func (db *RPMDatabase) query() (interface{}, error) {
+	cmd := exec.Command("/usr/bin/rpm", "-qa", "--queryformat", "%{NAME} %{VERSION}-%{RELEASE}\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		versions[fields[0]] = fields[1]
+	}
+	return versions, nil
+}
+
+// Version returns the installed version of pkg, or "" if it isn't installed
+// or the database hasn't been read yet.
+func (db *RPMDatabase) Version(pkg string) string {
+	value, err := db.cache.Snapshot()
+	if err != nil || value == nil {
+		return ""
+	}
+	return value.(map[string]string)[pkg]
+}
@@ -0,0 +1,26 @@
+package main
+
+// PackageInfo describes a single pending update so dashboards can drill down
+// from aggregate counts to the specific package and versions involved.
+type PackageInfo struct {
+	Name             string
+	CurrentVersion   string
+	AvailableVersion string
+	Origin           string
+}
+
+// SecurityUpdate counts pending updates of a given severity from a given origin.
+type SecurityUpdate struct {
+	Origin   string
+	Severity string
+	Count    int
+}
+
+// SecurityAware is implemented by package managers that can distinguish
+// security updates from routine ones and expose per-package version info.
+// Not every backend can do this cheaply, so it is an optional interface
+// rather than part of PackageManager; the collector type-asserts for it.
+type SecurityAware interface {
+	SecurityUpdates() ([]SecurityUpdate, error)
+	PackageDetails() ([]PackageInfo, error)
+}
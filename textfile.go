@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// textfileName is the file node_exporter's textfile collector picks up;
+// it must end in ".prom" and match no other collector's output in the
+// configured directory.
+const textfileName = "package_exporter.prom"
+
+// writeTextfile gathers registry once and writes the result to
+// <directory>/package_exporter.prom, renaming into place atomically so
+// node_exporter's textfile collector never reads a partial file.
+func writeTextfile(registry *prometheus.Registry, directory string) error {
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(directory, ".package_exporter-*.prom.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	encoder := expfmt.NewEncoder(tmp, expfmt.FmtText)
+	for _, mf := range metricFamilies {
+		if err := encoder.Encode(mf); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, filepath.Join(directory, textfileName))
+}